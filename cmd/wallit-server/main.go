@@ -0,0 +1,20 @@
+// Command wallit-server runs the wallit HTTP server on its own, without
+// the CLI's rendering flags. It's equivalent to `wallit -listen`, for
+// deployments that only ever want the server.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/weop/wallit/pkg/wallit"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "Address to serve GET /wallpaper on")
+	flag.Parse()
+
+	if err := wallit.ListenAndServe(*listen); err != nil {
+		log.Fatal(err)
+	}
+}
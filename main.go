@@ -3,159 +3,187 @@ package main
 import (
 	"flag"
 	"fmt"
-	"image"
 	"image/color"
-	"image/draw"
-	"image/png"
 	"log"
 	"os"
-	"strings"
 
-	"github.com/golang/freetype"
-	"github.com/golang/freetype/truetype"
+	"github.com/weop/wallit/pkg/wallit"
 )
 
-type Config struct {
-	quote      string
-	author     string
-	width      int
-	height     int
-	outputPath string
-	fontPath   string
-	scale      float64
-}
-
-func parseFlags() Config {
+// buildConfig parses the CLI flags into a wallit.Config plus the
+// CLI-only settings (output path, preset, listen address) that control
+// how Config is used rather than what it renders.
+func buildConfig() (cfg wallit.Config, outputPath, preset, listen string) {
 	quote := flag.String("quote", "[ Hello World ]", "The quote text")
 	author := flag.String("author", "", "The author of the quote")
 	width := flag.Int("width", 3840, "Width of the wallpaper")
 	height := flag.Int("height", 2160, "Height of the wallpaper")
-	outputPath := flag.String("output", "wallpaper.png", "Output file path")
+	outputPathFlag := flag.String("output", "wallpaper.png", "Output file path")
 	fontPath := flag.String("font", "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf", "Path to font file")
 	scale := flag.Float64("scale", 1.0, "Scale factor for font size")
+	format := flag.String("format", "png", "Output image format: png or jpeg")
+
+	bgType := flag.String("bg", "solid", "Background type: solid, gradient, radial, or image")
+	bgColor1Flag := flag.String("bg-color1", "000000", "Primary background color, as hex RRGGBB[AA]")
+	bgColor2Flag := flag.String("bg-color2", "000000", "Secondary background color for gradient/radial, as hex RRGGBB[AA]")
+	bgAngle := flag.Float64("bg-angle", 45, "Linear gradient angle in degrees")
+	template := flag.String("template", "", "Path to a JPEG/PNG image to use as the background (-bg image)")
+	textboxFlag := flag.String("textbox", "", "Translucent rgba box behind the text, as hex RRGGBBAA (empty disables)")
+
+	size := flag.String("size", "", "Output size as N (square) or WxH, overrides -width/-height")
+	presetFlag := flag.String("preset", "", "Named output size: 4k, 1080p, phone, instagram, twitter, or all")
+
+	shadowFlag := flag.Bool("shadow", false, "Draw a drop-shadow pass behind the text for readability over busy backgrounds")
+	shadowColorFlag := flag.String("shadow-color", "000000", "Shadow color, as hex RRGGBB[AA]")
+	shadowOffsetFlag := flag.String("shadow-offset", "1,1", "Shadow offset in pixels, as X,Y")
+	strokeFlag := flag.Bool("stroke", false, "Draw a cheap 8-direction outline behind the text before the fill pass")
+	strokeColorFlag := flag.String("stroke-color", "000000", "Outline color, as hex RRGGBB[AA]")
+	hintingFlag := flag.String("hinting", "full", "Font hinting: none, vertical, or full")
+
+	listenFlag := flag.String("listen", "", "Address to serve GET /wallpaper on instead of rendering a single file, e.g. :8080")
 
 	flag.Parse()
 
+	if *listenFlag != "" {
+		return wallit.Config{}, "", "", *listenFlag
+	}
+
 	if *quote == "" {
 		fmt.Println("No quote provided...")
 	}
 
-	return Config{
-		quote:      *quote,
-		author:     *author,
-		width:      *width,
-		height:     *height,
-		outputPath: *outputPath,
-		fontPath:   *fontPath,
-		scale:      *scale,
+	if *size != "" {
+		w, h, err := wallit.ParseSize(*size)
+		if err != nil {
+			log.Fatalf("invalid -size: %v", err)
+		}
+		*width, *height = w, h
+	}
+
+	if *presetFlag != "" && *presetFlag != "all" {
+		dims, ok := wallit.Presets[*presetFlag]
+		if !ok {
+			log.Fatalf("unknown -preset %q", *presetFlag)
+		}
+		*width, *height = dims[0], dims[1]
 	}
-}
 
-func createGradientBackground(width, height int) *image.RGBA {
-	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if *presetFlag != "all" {
+		if err := wallit.ValidateSize(*width, *height); err != nil {
+			log.Fatalf("invalid size: %v", err)
+		}
+	}
 
-	// Fill with black background
-	draw.Draw(img, img.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+	bgColor1, err := wallit.ParseHexColor(*bgColor1Flag)
+	if err != nil {
+		log.Fatalf("invalid -bg-color1: %v", err)
+	}
+	bgColor2, err := wallit.ParseHexColor(*bgColor2Flag)
+	if err != nil {
+		log.Fatalf("invalid -bg-color2: %v", err)
+	}
 
-	return img
-}
+	var textboxColor color.Color
+	if *textboxFlag != "" {
+		textboxColor, err = wallit.ParseHexColor(*textboxFlag)
+		if err != nil {
+			log.Fatalf("invalid -textbox: %v", err)
+		}
+	}
 
-func addText(img *image.RGBA, config Config) error {
-    c := freetype.NewContext()
-    c.SetDPI(72)
-
-    fontData, err := os.ReadFile(config.fontPath)
-    if err == nil {
-        font, err := truetype.Parse(fontData)
-        if err != nil {
-            return fmt.Errorf("failed to parse font: %v", err)
-        } else {
-            c.SetFont(font)
-        }
-    }
-
-    c.SetClip(img.Bounds())
-    c.SetDst(img)
-    c.SetSrc(image.White)
-
-    quoteSize := (float64(config.height) / 30) * config.scale
-    authorSize := quoteSize * 0.6 * config.scale
-
-    c.SetFontSize(quoteSize)
-    lines := wrapText(config.quote, config.width/int(quoteSize)*2)
-
-    y := float64(config.height)/2 - (float64(len(lines))*quoteSize)/2
-    for _, line := range lines {
-        textWidth := int(c.PointToFixed(quoteSize * float64(len(line)) * 0.6) >> 6)
-        x := (config.width - textWidth) / 2
-        pt := freetype.Pt(x, int(y))
-        _, err = c.DrawString(line, pt)
-        if err != nil {
-            return fmt.Errorf("failed to draw quote: %v", err)
-        }
-        y += quoteSize * 1.5
-    }
-
-    if config.author != "" {
-        c.SetFontSize(authorSize)
-        authorText := fmt.Sprintf("- %s  ", config.author)
-        textWidth := int(c.PointToFixed(authorSize * float64(len(authorText)) * 0.6) >> 6)
-        x := (config.width - textWidth) / 2
-        pt := freetype.Pt(x, int(y+authorSize))
-        _, err = c.DrawString(authorText, pt)
-        if err != nil {
-            return fmt.Errorf("failed to draw author: %v", err)
-        }
-    }
-
-    return nil
-}
+	shadowColor, err := wallit.ParseHexColor(*shadowColorFlag)
+	if err != nil {
+		log.Fatalf("invalid -shadow-color: %v", err)
+	}
+	shadowOffsetX, shadowOffsetY, err := wallit.ParseOffset(*shadowOffsetFlag)
+	if err != nil {
+		log.Fatalf("invalid -shadow-offset: %v", err)
+	}
+	strokeColor, err := wallit.ParseHexColor(*strokeColorFlag)
+	if err != nil {
+		log.Fatalf("invalid -stroke-color: %v", err)
+	}
+	hinting, err := wallit.ParseHinting(*hintingFlag)
+	if err != nil {
+		log.Fatalf("invalid -hinting: %v", err)
+	}
 
-func wrapText(text string, maxChars int) []string {
-	words := strings.Fields(text)
-	if len(words) == 0 {
-		return nil
+	cfg = wallit.Config{
+		Quote:    *quote,
+		Author:   *author,
+		Width:    *width,
+		Height:   *height,
+		FontPath: *fontPath,
+		Scale:    *scale,
+		Format:   *format,
+
+		BgType:       *bgType,
+		BgColor1:     bgColor1,
+		BgColor2:     bgColor2,
+		BgAngle:      *bgAngle,
+		TemplatePath: *template,
+		TextboxColor: textboxColor,
+
+		Shadow:        *shadowFlag,
+		ShadowColor:   shadowColor,
+		ShadowOffsetX: shadowOffsetX,
+		ShadowOffsetY: shadowOffsetY,
+
+		Stroke:      *strokeFlag,
+		StrokeColor: strokeColor,
+
+		Hinting: hinting,
 	}
 
-	var lines []string
-	currentLine := words[0]
+	return cfg, *outputPathFlag, *presetFlag, ""
+}
 
-	for _, word := range words[1:] {
-		if len(currentLine)+1+len(word) <= maxChars {
-			currentLine += " " + word
-		} else {
-			lines = append(lines, currentLine)
-			currentLine = word
-		}
+// renderToFile runs the full wallit pipeline for cfg and writes the
+// result to outputPath.
+func renderToFile(cfg wallit.Config, outputPath string) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer f.Close()
+
+	if err := wallit.Render(cfg, f); err != nil {
+		return err
 	}
-	lines = append(lines, currentLine)
 
-	return lines
+	fmt.Printf("Wallpaper generated to: %s\n", outputPath)
+	return nil
 }
 
 func main() {
-	config := parseFlags()
-
-	// Create background
-	img := createGradientBackground(config.width, config.height)
+	cfg, outputPath, preset, listen := buildConfig()
 
-	// Add text
-	err := addText(img, config)
-	if err != nil {
-		log.Fatalf("Failed to add text: %v", err)
+	if listen != "" {
+		if err := wallit.ListenAndServe(listen); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-	// Save the image
-	f, err := os.Create(config.outputPath)
-	if err != nil {
-		log.Fatalf("Failed to create output file: %v", err)
+	if preset != "all" {
+		if err := renderToFile(cfg, outputPath); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
-	defer f.Close()
 
-	err = png.Encode(f, img)
-	if err != nil {
-		log.Fatalf("Failed to encode image: %v", err)
+	ext := "png"
+	if cfg.Format == "jpeg" {
+		ext = "jpg"
 	}
 
-	fmt.Printf("Wallpaper generated to: %s\n", config.outputPath)
-}
\ No newline at end of file
+	for _, name := range wallit.PresetOrder {
+		dims := wallit.Presets[name]
+		presetCfg := cfg
+		presetCfg.Width, presetCfg.Height = dims[0], dims[1]
+
+		if err := renderToFile(presetCfg, fmt.Sprintf("wallpaper_%s.%s", name, ext)); err != nil {
+			log.Fatalf("preset %s: %v", name, err)
+		}
+	}
+}
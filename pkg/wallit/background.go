@@ -0,0 +1,204 @@
+package wallit
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	"math"
+	"os"
+	"strings"
+)
+
+// Background produces the base canvas a quote is rendered over.
+type Background interface {
+	Render(width, height int) (*image.RGBA, error)
+}
+
+// SolidBackground fills the canvas with a single color.
+type SolidBackground struct {
+	Color color.Color
+}
+
+func (b SolidBackground) Render(width, height int) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{b.Color}, image.Point{}, draw.Src)
+	return img, nil
+}
+
+// LinearGradientBackground interpolates between two colors along a line
+// at AngleDegrees (0 is left-to-right, 90 is top-to-bottom).
+type LinearGradientBackground struct {
+	Color1, Color2 color.Color
+	AngleDegrees   float64
+}
+
+func (b LinearGradientBackground) Render(width, height int) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	r1, g1, bl1, a1 := b.Color1.RGBA()
+	r2, g2, bl2, a2 := b.Color2.RGBA()
+
+	theta := b.AngleDegrees * math.Pi / 180
+	dx, dy := math.Cos(theta), math.Sin(theta)
+
+	// Project every corner onto the gradient axis so t spans [0,1] across
+	// the whole canvas regardless of angle.
+	corners := [4][2]int{{0, 0}, {width, 0}, {0, height}, {width, height}}
+	minProj, maxProj := math.Inf(1), math.Inf(-1)
+	for _, corner := range corners {
+		proj := float64(corner[0])*dx + float64(corner[1])*dy
+		minProj = math.Min(minProj, proj)
+		maxProj = math.Max(maxProj, proj)
+	}
+	span := maxProj - minProj
+	if span == 0 {
+		span = 1
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			proj := float64(x)*dx + float64(y)*dy
+			t := (proj - minProj) / span
+			img.Set(x, y, lerpColor(r1, g1, bl1, a1, r2, g2, bl2, a2, t))
+		}
+	}
+
+	return img, nil
+}
+
+// RadialGradientBackground interpolates from Color1 at the canvas center
+// to Color2 at its corners.
+type RadialGradientBackground struct {
+	Color1, Color2 color.Color
+}
+
+func (b RadialGradientBackground) Render(width, height int) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	r1, g1, bl1, a1 := b.Color1.RGBA()
+	r2, g2, bl2, a2 := b.Color2.RGBA()
+
+	cx, cy := float64(width)/2, float64(height)/2
+	maxDist := math.Hypot(cx, cy)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			t := math.Hypot(float64(x)-cx, float64(y)-cy) / maxDist
+			if t > 1 {
+				t = 1
+			}
+			img.Set(x, y, lerpColor(r1, g1, bl1, a1, r2, g2, bl2, a2, t))
+		}
+	}
+
+	return img, nil
+}
+
+// lerpColor linearly interpolates between two colors already expanded to
+// 16-bit RGBA components, as returned by color.Color.RGBA().
+func lerpColor(r1, g1, b1, a1, r2, g2, b2, a2 uint32, t float64) color.RGBA {
+	lerp := func(v1, v2 uint32) uint8 {
+		return uint8((float64(v1)*(1-t) + float64(v2)*t) / 257)
+	}
+	return color.RGBA{R: lerp(r1, r2), G: lerp(g1, g2), B: lerp(b1, b2), A: lerp(a1, a2)}
+}
+
+// ImageBackground decodes a user-supplied JPEG/PNG template and scales it
+// to cover the canvas, cropping whichever dimension overflows.
+type ImageBackground struct {
+	Path string
+}
+
+func (b ImageBackground) Render(width, height int) (*image.RGBA, error) {
+	f, err := os.Open(b.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open template: %v", err)
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode template: %v", err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	drawFitCentered(img, src)
+	return img, nil
+}
+
+// drawFitCentered scales src to cover dst's bounds (preserving aspect
+// ratio) and draws it centered, cropping whichever dimension overflows.
+func drawFitCentered(dst *image.RGBA, src image.Image) {
+	dw, dh := dst.Bounds().Dx(), dst.Bounds().Dy()
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	if sw == 0 || sh == 0 {
+		return
+	}
+
+	scale := math.Max(float64(dw)/float64(sw), float64(dh)/float64(sh))
+	scaledW := int(float64(sw) * scale)
+	scaledH := int(float64(sh) * scale)
+	offsetX := (dw - scaledW) / 2
+	offsetY := (dh - scaledH) / 2
+
+	for y := 0; y < scaledH; y++ {
+		dy := y + offsetY
+		if dy < 0 || dy >= dh {
+			continue
+		}
+		sy := sb.Min.Y + int(float64(y)/scale)
+		for x := 0; x < scaledW; x++ {
+			dx := x + offsetX
+			if dx < 0 || dx >= dw {
+				continue
+			}
+			sx := sb.Min.X + int(float64(x)/scale)
+			dst.Set(dx, dy, src.At(sx, sy))
+		}
+	}
+}
+
+// ParseHexColor parses a "RRGGBB" or "RRGGBBAA" string (with or without a
+// leading '#') into a color.Color.
+func ParseHexColor(s string) (color.Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	var r, g, b, a uint8
+	a = 0xff
+
+	switch len(s) {
+	case 6:
+		if _, err := fmt.Sscanf(s, "%02x%02x%02x", &r, &g, &b); err != nil {
+			return nil, fmt.Errorf("invalid color %q: %v", s, err)
+		}
+	case 8:
+		if _, err := fmt.Sscanf(s, "%02x%02x%02x%02x", &r, &g, &b, &a); err != nil {
+			return nil, fmt.Errorf("invalid color %q: %v", s, err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid color %q: want RRGGBB or RRGGBBAA", s)
+	}
+
+	return color.RGBA{R: r, G: g, B: b, A: a}, nil
+}
+
+// newBackground builds the Background implementation selected by
+// cfg.BgType, using the colors, angle, and template path also carried on
+// Config.
+func newBackground(cfg Config) (Background, error) {
+	switch cfg.BgType {
+	case "solid", "":
+		return SolidBackground{Color: cfg.BgColor1}, nil
+	case "gradient":
+		return LinearGradientBackground{Color1: cfg.BgColor1, Color2: cfg.BgColor2, AngleDegrees: cfg.BgAngle}, nil
+	case "radial":
+		return RadialGradientBackground{Color1: cfg.BgColor1, Color2: cfg.BgColor2}, nil
+	case "image":
+		if cfg.TemplatePath == "" {
+			return nil, fmt.Errorf("-bg image requires -template")
+		}
+		return ImageBackground{Path: cfg.TemplatePath}, nil
+	default:
+		return nil, fmt.Errorf("unknown -bg %q: want solid, gradient, radial, or image", cfg.BgType)
+	}
+}
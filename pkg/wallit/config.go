@@ -0,0 +1,73 @@
+// Package wallit renders a quote over a generated or user-supplied
+// background and encodes the result as a wallpaper image. It is the
+// library extracted from the wallit CLI so the same rendering pipeline
+// can be driven from the command line or from an HTTP server.
+package wallit
+
+import (
+	"image/color"
+
+	"golang.org/x/image/font"
+)
+
+// Config describes a single wallpaper render: the quote and author text,
+// the output dimensions, the background, and the text-readability passes
+// applied on top of it.
+type Config struct {
+	Quote  string
+	Author string
+	Width  int
+	Height int
+
+	FontPath string
+	Scale    float64
+
+	BgType       string
+	BgColor1     color.Color
+	BgColor2     color.Color
+	BgAngle      float64
+	TemplatePath string
+	TextboxColor color.Color
+
+	Shadow        bool
+	ShadowColor   color.Color
+	ShadowOffsetX int
+	ShadowOffsetY int
+
+	Stroke      bool
+	StrokeColor color.Color
+
+	Hinting font.Hinting
+
+	// Format selects the encoding Render writes: "png" (the default) or
+	// "jpeg".
+	Format string
+}
+
+// DefaultConfig returns a Config with the same defaults the CLI flags
+// fall back to, so callers (the CLI, the HTTP server) only need to
+// override what they care about.
+func DefaultConfig() Config {
+	return Config{
+		Quote:    "[ Hello World ]",
+		Width:    3840,
+		Height:   2160,
+		FontPath: "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+		Scale:    1.0,
+
+		BgType:   "solid",
+		BgColor1: color.RGBA{A: 0xff},
+		BgColor2: color.RGBA{A: 0xff},
+		BgAngle:  45,
+
+		ShadowColor:   color.RGBA{A: 0xff},
+		ShadowOffsetX: 1,
+		ShadowOffsetY: 1,
+
+		StrokeColor: color.RGBA{A: 0xff},
+
+		Hinting: font.HintingFull,
+
+		Format: "png",
+	}
+}
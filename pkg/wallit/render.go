@@ -0,0 +1,40 @@
+package wallit
+
+import (
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// Render runs the full background+text pipeline for cfg and encodes the
+// result to w as PNG, or as JPEG if cfg.Format is "jpeg".
+func Render(cfg Config, w io.Writer) error {
+	bg, err := newBackground(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid background: %v", err)
+	}
+	img, err := bg.Render(cfg.Width, cfg.Height)
+	if err != nil {
+		return fmt.Errorf("failed to render background: %v", err)
+	}
+
+	if err := addText(img, cfg); err != nil {
+		return fmt.Errorf("failed to add text: %v", err)
+	}
+
+	switch cfg.Format {
+	case "jpeg":
+		if err := jpeg.Encode(w, img, nil); err != nil {
+			return fmt.Errorf("failed to encode image: %v", err)
+		}
+	case "png", "":
+		if err := png.Encode(w, img); err != nil {
+			return fmt.Errorf("failed to encode image: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown format %q: want png or jpeg", cfg.Format)
+	}
+
+	return nil
+}
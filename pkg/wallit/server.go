@@ -0,0 +1,183 @@
+package wallit
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// defaultCacheSize bounds the number of rendered images NewMux keeps in
+// memory, keyed on the full request query string.
+const defaultCacheSize = 128
+
+// maxQueryDimension bounds width/height accepted from the network-facing
+// /wallpaper handler, so a request like ?width=100000&height=100000 can't
+// force an oversized allocation (width*height*4 bytes) into the cache.
+// It's well above every built-in preset (the largest, 4k, is 3840x2160).
+const maxQueryDimension = 8192
+
+// imageCache is a small fixed-capacity in-memory LRU cache from a
+// serialized request (the query string) to an already-encoded image, so
+// repeated identical /wallpaper requests don't re-render.
+type imageCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type cacheEntry struct {
+	key  string
+	data []byte
+}
+
+func newImageCache(capacity int) *imageCache {
+	return &imageCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *imageCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).data, true
+}
+
+func (c *imageCache) put(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*cacheEntry).data = data
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&cacheEntry{key: key, data: data})
+	c.items[key] = el
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).key)
+		}
+	}
+}
+
+// NewMux builds the HTTP handler exposing GET /wallpaper, backed by an
+// in-memory cache of up to cacheSize rendered images.
+func NewMux(cacheSize int) *http.ServeMux {
+	cache := newImageCache(cacheSize)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/wallpaper", func(w http.ResponseWriter, r *http.Request) {
+		serveWallpaper(w, r, cache)
+	})
+	return mux
+}
+
+// serveWallpaper handles GET /wallpaper?quote=...&author=...&width=...
+// &height=..., returning the cached render for this exact query if
+// present. template is deliberately not accepted here: it would let an
+// HTTP caller make the server read and stream back arbitrary local
+// files, so image backgrounds remain available only through the CLI's
+// -template flag.
+func serveWallpaper(w http.ResponseWriter, r *http.Request, cache *imageCache) {
+	query := r.URL.Query()
+	key := query.Encode()
+
+	if data, ok := cache.get(key); ok {
+		writeImage(w, query.Get("format"), data)
+		return
+	}
+
+	cfg, err := configFromQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := Render(cfg, &buf); err != nil {
+		http.Error(w, fmt.Sprintf("failed to render wallpaper: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	data := buf.Bytes()
+	cache.put(key, data)
+	writeImage(w, cfg.Format, data)
+}
+
+// writeImage sets the Content-Type matching format and streams data to
+// w, mirroring the encoding Render chose.
+func writeImage(w http.ResponseWriter, format string, data []byte) {
+	if format == "jpeg" {
+		w.Header().Set("Content-Type", "image/jpeg")
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+	}
+	w.Write(data)
+}
+
+// configFromQuery builds a Config from the query parameters of a
+// GET /wallpaper request, starting from DefaultConfig and overriding
+// whatever the caller provided.
+func configFromQuery(query url.Values) (Config, error) {
+	cfg := DefaultConfig()
+
+	if v := query.Get("quote"); v != "" {
+		cfg.Quote = v
+	}
+	if v := query.Get("author"); v != "" {
+		cfg.Author = v
+	}
+	if v := query.Get("width"); v != "" {
+		width, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid width %q: %v", v, err)
+		}
+		cfg.Width = width
+	}
+	if v := query.Get("height"); v != "" {
+		height, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid height %q: %v", v, err)
+		}
+		cfg.Height = height
+	}
+	if query.Get("template") != "" {
+		return Config{}, fmt.Errorf("template is not supported over HTTP: image backgrounds must come from a local -template file")
+	}
+	if v := query.Get("format"); v != "" {
+		cfg.Format = v
+	}
+
+	if err := ValidateSize(cfg.Width, cfg.Height); err != nil {
+		return Config{}, err
+	}
+	if cfg.Width > maxQueryDimension || cfg.Height > maxQueryDimension {
+		return Config{}, fmt.Errorf("width and height must be at most %d, got %dx%d", maxQueryDimension, cfg.Width, cfg.Height)
+	}
+
+	return cfg, nil
+}
+
+// ListenAndServe starts the wallpaper HTTP server on addr. It blocks
+// until the server exits, returning whatever http.ListenAndServe does.
+func ListenAndServe(addr string) error {
+	log.Printf("wallit server listening on %s", addr)
+	return http.ListenAndServe(addr, NewMux(defaultCacheSize))
+}
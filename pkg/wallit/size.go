@@ -0,0 +1,86 @@
+package wallit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+)
+
+// Presets maps named output sizes to width/height pairs.
+var Presets = map[string][2]int{
+	"4k":        {3840, 2160},
+	"1080p":     {1920, 1080},
+	"phone":     {1080, 1920},
+	"instagram": {1080, 1080},
+	"twitter":   {1500, 500},
+}
+
+// PresetOrder fixes the iteration order for -preset all so repeated runs
+// produce the same files in the same sequence.
+var PresetOrder = []string{"4k", "1080p", "phone", "instagram", "twitter"}
+
+// ParseSize parses a -size value of either "N" (a square of side N) or
+// "WxH", as in the text-pic CLI.
+func ParseSize(s string) (width, height int, err error) {
+	if parts := strings.Split(s, "x"); len(parts) == 2 {
+		width, err = strconv.Atoi(parts[0])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid width %q: %v", parts[0], err)
+		}
+		height, err = strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid height %q: %v", parts[1], err)
+		}
+		return width, height, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid -size %q: want N or WxH", s)
+	}
+	return n, n, nil
+}
+
+// ValidateSize errors cleanly instead of letting a non-positive width or
+// height produce a degenerate image.
+func ValidateSize(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("width and height must be positive, got %dx%d", width, height)
+	}
+	return nil
+}
+
+// ParseOffset parses a -shadow-offset value of the form "X,Y" into pixel
+// offsets, as strconv.Atoi pairs rather than a single combined number.
+func ParseOffset(s string) (x, y int, err error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid offset %q: want X,Y", s)
+	}
+	x, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid offset x %q: %v", parts[0], err)
+	}
+	y, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid offset y %q: %v", parts[1], err)
+	}
+	return x, y, nil
+}
+
+// ParseHinting maps a -hinting flag value to the font.Hinting the
+// rendering context should use.
+func ParseHinting(s string) (font.Hinting, error) {
+	switch s {
+	case "none":
+		return font.HintingNone, nil
+	case "vertical":
+		return font.HintingVertical, nil
+	case "full", "":
+		return font.HintingFull, nil
+	default:
+		return font.HintingNone, fmt.Errorf("invalid hinting %q: want none, vertical, or full", s)
+	}
+}
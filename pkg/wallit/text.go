@@ -0,0 +1,384 @@
+package wallit
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// newFace builds a font.Face for ttf at the given point size and hinting,
+// used for pixel-accurate measurement via font.MeasureString /
+// GlyphAdvance. hinting must match what the draw context uses, or glyphs
+// measure at different advances than they're rasterized at.
+func newFace(ttf *truetype.Font, size float64, hinting font.Hinting) font.Face {
+	return truetype.NewFace(ttf, &truetype.Options{
+		Size:    size,
+		DPI:     72,
+		Hinting: hinting,
+	})
+}
+
+// loadTTF reads and parses the TrueType font at fontPath. It fails fast if
+// fontPath is empty or the file does not exist, rather than letting
+// truetype.Parse produce a more opaque error.
+func loadTTF(fontPath string) (*truetype.Font, error) {
+	if fontPath == "" {
+		return nil, fmt.Errorf("no font path configured")
+	}
+	if _, err := os.Stat(fontPath); err != nil {
+		return nil, fmt.Errorf("font not found: %v", err)
+	}
+
+	fontData, err := os.ReadFile(fontPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %v", err)
+	}
+
+	ttf, err := truetype.Parse(fontData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %v", err)
+	}
+
+	return ttf, nil
+}
+
+func addText(img *image.RGBA, cfg Config) error {
+	ttf, err := loadTTF(cfg.FontPath)
+	if err != nil {
+		log.Printf("no usable font at %q (%v), falling back to embedded font", cfg.FontPath, err)
+		return addTextEmbedded(img, cfg)
+	}
+
+	c := freetype.NewContext()
+	c.SetDPI(72)
+	c.SetFont(ttf)
+	c.SetHinting(cfg.Hinting)
+
+	c.SetClip(img.Bounds())
+	c.SetDst(img)
+
+	quoteSize := (float64(cfg.Height) / 30) * cfg.Scale
+	authorSize := quoteSize * 0.6 * cfg.Scale
+
+	c.SetFontSize(quoteSize)
+	quoteFace := newFace(ttf, quoteSize, cfg.Hinting)
+	defer quoteFace.Close()
+
+	maxPixelWidth := fixed.I(cfg.Width * 9 / 10)
+	lines := wrapText(cfg.Quote, quoteFace, maxPixelWidth)
+
+	lineHeight := fixedToFloat(quoteFace.Metrics().Height)
+	y := float64(cfg.Height)/2 - (float64(len(lines))*lineHeight)/2
+	y += fixedToFloat(quoteFace.Metrics().Ascent)
+
+	if cfg.TextboxColor != nil {
+		drawTextBox(img, cfg, lines, quoteFace, y, lineHeight, authorSize)
+	}
+
+	for _, line := range lines {
+		textWidth := fixedToFloat(font.MeasureString(quoteFace, line))
+		x := (float64(cfg.Width) - textWidth) / 2
+		if err := drawEffectString(c, line, int(x), int(y), cfg); err != nil {
+			return fmt.Errorf("failed to draw quote: %v", err)
+		}
+		y += lineHeight
+	}
+
+	if cfg.Author != "" {
+		c.SetFontSize(authorSize)
+		authorFace := newFace(ttf, authorSize, cfg.Hinting)
+		defer authorFace.Close()
+
+		authorText := fmt.Sprintf("- %s  ", cfg.Author)
+		textWidth := fixedToFloat(font.MeasureString(authorFace, authorText))
+		x := (float64(cfg.Width) - textWidth) / 2
+		if err := drawEffectString(c, authorText, int(x), int(y+authorSize), cfg); err != nil {
+			return fmt.Errorf("failed to draw author: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// strokeOffsets are the 8 unit offsets around a glyph's baseline used to
+// fake an outline by drawing the string repeatedly, without needing a
+// true path stroker.
+var strokeOffsets = [][2]int{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+// drawEffectString draws text at (x, y) through c, laying down an
+// optional shadow pass and/or 8-direction outline pass in the configured
+// colors before the final white fill pass, so text stays legible over
+// busy image backgrounds.
+func drawEffectString(c *freetype.Context, text string, x, y int, cfg Config) error {
+	if cfg.Shadow {
+		c.SetSrc(image.NewUniform(cfg.ShadowColor))
+		pt := freetype.Pt(x+cfg.ShadowOffsetX, y+cfg.ShadowOffsetY)
+		if _, err := c.DrawString(text, pt); err != nil {
+			return fmt.Errorf("failed to draw shadow: %v", err)
+		}
+	}
+
+	if cfg.Stroke {
+		c.SetSrc(image.NewUniform(cfg.StrokeColor))
+		for _, d := range strokeOffsets {
+			pt := freetype.Pt(x+d[0], y+d[1])
+			if _, err := c.DrawString(text, pt); err != nil {
+				return fmt.Errorf("failed to draw outline: %v", err)
+			}
+		}
+	}
+
+	c.SetSrc(image.White)
+	if _, err := c.DrawString(text, freetype.Pt(x, y)); err != nil {
+		return fmt.Errorf("failed to draw text: %v", err)
+	}
+	return nil
+}
+
+// drawEffectStringBitmap is the font.Drawer equivalent of
+// drawEffectString, used by addTextEmbedded so the bitmap fallback face
+// also supports shadow and outline passes.
+func drawEffectStringBitmap(drawer *font.Drawer, text string, x, y int, cfg Config) {
+	if cfg.Shadow {
+		drawer.Src = image.NewUniform(cfg.ShadowColor)
+		drawer.Dot = fixed.P(x+cfg.ShadowOffsetX, y+cfg.ShadowOffsetY)
+		drawer.DrawString(text)
+	}
+
+	if cfg.Stroke {
+		drawer.Src = image.NewUniform(cfg.StrokeColor)
+		for _, d := range strokeOffsets {
+			drawer.Dot = fixed.P(x+d[0], y+d[1])
+			drawer.DrawString(text)
+		}
+	}
+
+	drawer.Src = image.White
+	drawer.Dot = fixed.P(x, y)
+	drawer.DrawString(text)
+}
+
+// drawTextBox paints a translucent rectangle behind the quote (and author
+// line, if any) so text stays legible over busy image backgrounds.
+func drawTextBox(img *image.RGBA, cfg Config, lines []string, face font.Face, yStart, lineHeight, authorSize float64) {
+	maxWidth := 0.0
+	for _, line := range lines {
+		if w := fixedToFloat(font.MeasureString(face, line)); w > maxWidth {
+			maxWidth = w
+		}
+	}
+
+	bottom := yStart + float64(len(lines))*lineHeight
+	if cfg.Author != "" {
+		bottom += authorSize * 1.5
+	}
+
+	padding := lineHeight * 0.3
+	top := yStart - fixedToFloat(face.Metrics().Ascent) - padding
+	rect := image.Rect(
+		int((float64(cfg.Width)-maxWidth)/2-padding),
+		int(top),
+		int((float64(cfg.Width)+maxWidth)/2+padding),
+		int(bottom+padding),
+	)
+
+	draw.Draw(img, rect, &image.Uniform{cfg.TextboxColor}, image.Point{}, draw.Over)
+}
+
+// addTextEmbedded renders the quote with the stock basicfont.Face7x13
+// bitmap face so wallit still produces readable output when no system TTF
+// is available, instead of the blank canvas the old silent-continue
+// behavior produced. The face itself is fixed at 7x13 and ignores
+// cfg.Scale, so the text is drawn to a small transparent buffer at that
+// native size and then nearest-neighbor upscaled to roughly the size the
+// TTF path would have used — otherwise a 4K+ canvas gets an unreadable
+// pinprick of text instead of the near-blank image this fallback exists
+// to replace.
+func addTextEmbedded(img *image.RGBA, cfg Config) error {
+	face := basicfont.Face7x13
+
+	lineHeight := fixedToFloat(face.Metrics().Height)
+	ascent := fixedToFloat(face.Metrics().Ascent)
+	descent := fixedToFloat(face.Metrics().Descent)
+
+	targetLineHeight := (float64(cfg.Height) / 30) * cfg.Scale
+	scale := targetLineHeight / lineHeight
+	if scale < 1 {
+		scale = 1
+	}
+
+	maxPixelWidth := fixed.I(int(float64(cfg.Width) * 9 / 10 / scale))
+	lines := wrapText(cfg.Quote, face, maxPixelWidth)
+
+	authorText := ""
+	if cfg.Author != "" {
+		authorText = fmt.Sprintf("- %s  ", cfg.Author)
+	}
+
+	bufWidth := 0.0
+	for _, line := range lines {
+		if w := fixedToFloat(font.MeasureString(face, line)); w > bufWidth {
+			bufWidth = w
+		}
+	}
+	if w := fixedToFloat(font.MeasureString(face, authorText)); w > bufWidth {
+		bufWidth = w
+	}
+
+	bufHeight := ascent + float64(len(lines))*lineHeight + descent
+	if authorText != "" {
+		bufHeight += lineHeight
+	}
+
+	const margin = 4 // room for the shadow/outline passes to bleed into
+	buf := image.NewRGBA(image.Rect(0, 0, int(bufWidth)+2*margin, int(bufHeight)+2*margin))
+
+	drawer := &font.Drawer{
+		Dst:  buf,
+		Src:  image.White,
+		Face: face,
+	}
+
+	y := margin + ascent
+	for _, line := range lines {
+		textWidth := fixedToFloat(font.MeasureString(face, line))
+		x := margin + (bufWidth-textWidth)/2
+		drawEffectStringBitmap(drawer, line, int(x), int(y), cfg)
+		y += lineHeight
+	}
+
+	if authorText != "" {
+		textWidth := fixedToFloat(font.MeasureString(face, authorText))
+		x := margin + (bufWidth-textWidth)/2
+		drawEffectStringBitmap(drawer, authorText, int(x), int(y), cfg)
+	}
+
+	scaled := nearestScaledImage{src: buf, scale: scale}
+	sb := scaled.Bounds()
+	offsetX := (cfg.Width - sb.Dx()) / 2
+	offsetY := (cfg.Height - sb.Dy()) / 2
+	dstRect := image.Rect(offsetX, offsetY, offsetX+sb.Dx(), offsetY+sb.Dy())
+	if cfg.TextboxColor != nil {
+		draw.Draw(img, dstRect, &image.Uniform{cfg.TextboxColor}, image.Point{}, draw.Over)
+	}
+	draw.Draw(img, dstRect, scaled, image.Point{}, draw.Over)
+
+	return nil
+}
+
+// nearestScaledImage lazily upsamples src by a linear factor using
+// nearest-neighbor sampling, so draw.Draw can composite it straight onto
+// a destination image without an intermediate scaled copy.
+type nearestScaledImage struct {
+	src   image.Image
+	scale float64
+}
+
+func (s nearestScaledImage) ColorModel() color.Model {
+	return s.src.ColorModel()
+}
+
+func (s nearestScaledImage) Bounds() image.Rectangle {
+	b := s.src.Bounds()
+	return image.Rect(0, 0, int(float64(b.Dx())*s.scale), int(float64(b.Dy())*s.scale))
+}
+
+func (s nearestScaledImage) At(x, y int) color.Color {
+	b := s.src.Bounds()
+	sx := b.Min.X + int(float64(x)/s.scale)
+	sy := b.Min.Y + int(float64(y)/s.scale)
+	return s.src.At(sx, sy)
+}
+
+// fixedToFloat converts a 26.6 fixed-point value (as returned by
+// font.MeasureString / face.Metrics()) to a plain float64 in pixels.
+func fixedToFloat(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}
+
+// wrapText word-wraps text so that no line exceeds maxPixelWidth when
+// rendered with face, measuring each candidate line with
+// font.MeasureString instead of counting characters. Words that are
+// themselves wider than maxPixelWidth are broken glyph by glyph.
+func wrapText(text string, face font.Face, maxPixelWidth fixed.Int26_6) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var lines []string
+	currentLine := ""
+
+	appendWord := func(word string) {
+		if currentLine == "" {
+			currentLine = word
+			return
+		}
+		candidate := currentLine + " " + word
+		if font.MeasureString(face, candidate) <= maxPixelWidth {
+			currentLine = candidate
+			return
+		}
+		lines = append(lines, currentLine)
+		currentLine = word
+	}
+
+	for _, word := range words {
+		if font.MeasureString(face, word) > maxPixelWidth {
+			if currentLine != "" {
+				lines = append(lines, currentLine)
+				currentLine = ""
+			}
+			lines = append(lines, breakWordByGlyph(word, face, maxPixelWidth)...)
+			continue
+		}
+		appendWord(word)
+	}
+
+	if currentLine != "" {
+		lines = append(lines, currentLine)
+	}
+
+	return lines
+}
+
+// breakWordByGlyph splits a single word that is wider than maxPixelWidth
+// into chunks that each fit, advancing glyph by glyph.
+func breakWordByGlyph(word string, face font.Face, maxPixelWidth fixed.Int26_6) []string {
+	var chunks []string
+	var chunk []rune
+	var width fixed.Int26_6
+
+	for _, r := range word {
+		advance, ok := face.GlyphAdvance(r)
+		if !ok {
+			continue
+		}
+		if len(chunk) > 0 && width+advance > maxPixelWidth {
+			chunks = append(chunks, string(chunk))
+			chunk = nil
+			width = 0
+		}
+		chunk = append(chunk, r)
+		width += advance
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, string(chunk))
+	}
+
+	return chunks
+}